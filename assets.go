@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -48,7 +49,9 @@ type StreamInfo struct {
 	Height    int    `json:"height"`
 }
 
-func (cfg apiConfig) getVideoAspectRatio(filePath string) (string, error) {
+// getVideoDimensions runs ffprobe against filePath and returns the width and
+// height of the first video stream found.
+func (cfg apiConfig) getVideoDimensions(filePath string) (int, int, error) {
 	cmd := exec.Command("ffprobe",
 		"-v", "error",
 		"-print_format", "json",
@@ -63,7 +66,7 @@ func (cfg apiConfig) getVideoAspectRatio(filePath string) (string, error) {
 	// Run the command.
 	err := cmd.Run()
 	if err != nil {
-		return "", fmt.Errorf(
+		return 0, 0, fmt.Errorf(
 			"ffprobe execution failed for '%s': %w. Stderr: %s",
 			filePath,
 			err,
@@ -72,7 +75,7 @@ func (cfg apiConfig) getVideoAspectRatio(filePath string) (string, error) {
 	}
 	var ffprobeData FFProbeOutput
 	if err := json.Unmarshal(outBuf.Bytes(), &ffprobeData); err != nil {
-		return "", fmt.Errorf(
+		return 0, 0, fmt.Errorf(
 			"failed to unmarshal ffprobe JSON output for '%s': %w. Output: %s",
 			filePath,
 			err,
@@ -92,16 +95,24 @@ func (cfg apiConfig) getVideoAspectRatio(filePath string) (string, error) {
 		}
 	}
 	if !foundVideoStream {
-		return "", fmt.Errorf("no video stream found in '%s'", filePath)
+		return 0, 0, fmt.Errorf("no video stream found in '%s'", filePath)
 	}
 	if videoWidth <= 0 || videoHeight <= 0 {
-		return "", fmt.Errorf(
+		return 0, 0, fmt.Errorf(
 			"video stream in '%s' has invalid dimensions: width=%d, height=%d",
 			filePath,
 			videoWidth,
 			videoHeight,
 		)
 	}
+	return videoWidth, videoHeight, nil
+}
+
+func (cfg apiConfig) getVideoAspectRatio(filePath string) (string, error) {
+	videoWidth, videoHeight, err := cfg.getVideoDimensions(filePath)
+	if err != nil {
+		return "", err
+	}
 	ratio := float64(videoWidth) / float64(videoHeight)
 	const epsilon = 0.02
 	sixteenNineRatio := 16.0 / 9.0
@@ -117,6 +128,19 @@ func (cfg apiConfig) getVideoAspectRatio(filePath string) (string, error) {
 	return "other", nil
 }
 
+// orientationPrefix maps a getVideoAspectRatio result to the S3 prefix
+// videos and their derived assets (thumbnails, previews) are grouped under.
+func orientationPrefix(ratio string) string {
+	switch ratio {
+	case "16:9":
+		return "landscape"
+	case "9:16":
+		return "portrait"
+	default:
+		return "other"
+	}
+}
+
 func (cfg apiConfig) processVideoForFastStart(filePath string) (string, error) {
 
 	outputPath := filePath + ".faststart.mp4"
@@ -157,3 +181,98 @@ func (cfg apiConfig) processVideoForFastStart(filePath string) (string, error) {
 
 	return filePath, nil
 }
+
+type ffprobeFormat struct {
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+// getVideoDuration runs ffprobe against filePath and returns its duration
+// in seconds.
+func (cfg apiConfig) getVideoDuration(filePath string) (float64, error) {
+	cmd := exec.Command("ffprobe",
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		filePath,
+	)
+	var outBuf bytes.Buffer
+	var errBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	if err := cmd.Run(); err != nil {
+		return 0, fmt.Errorf(
+			"ffprobe execution failed for '%s': %w. Stderr: %s",
+			filePath,
+			err,
+			errBuf.String(),
+		)
+	}
+
+	var probed ffprobeFormat
+	if err := json.Unmarshal(outBuf.Bytes(), &probed); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal ffprobe JSON output for '%s': %w", filePath, err)
+	}
+
+	duration, err := strconv.ParseFloat(probed.Format.Duration, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse duration for '%s': %w", filePath, err)
+	}
+	return duration, nil
+}
+
+// generateThumbnail extracts a single JPEG frame from filePath at atSeconds
+// and returns the path to it in a freshly created temp directory.
+func (cfg apiConfig) generateThumbnail(filePath string, atSeconds float64) (string, error) {
+	outDir, err := os.MkdirTemp("", "tubely-thumb-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create thumbnail output dir: %w", err)
+	}
+	outputPath := filepath.Join(outDir, "thumbnail.jpg")
+
+	cmd := exec.Command("ffmpeg",
+		"-ss", fmt.Sprintf("%.2f", atSeconds),
+		"-i", filePath,
+		"-vframes", "1",
+		"-q:v", "2",
+		outputPath,
+	)
+	var errBuf bytes.Buffer
+	cmd.Stderr = &errBuf
+	if err := cmd.Run(); err != nil {
+		os.RemoveAll(outDir)
+		return "", fmt.Errorf("ffmpeg thumbnail extraction failed for '%s': %w. Stderr: %s", filePath, err, errBuf.String())
+	}
+
+	return outputPath, nil
+}
+
+// generateAnimatedPreview builds a short, muted webp preview loop from the
+// first 5 seconds of filePath and returns its path in a freshly created
+// temp directory.
+func (cfg apiConfig) generateAnimatedPreview(filePath string) (string, error) {
+	outDir, err := os.MkdirTemp("", "tubely-preview-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create preview output dir: %w", err)
+	}
+	outputPath := filepath.Join(outDir, "preview.webp")
+
+	cmd := exec.Command("ffmpeg",
+		"-i", filePath,
+		"-t", "5",
+		"-vf", "scale=320:-1,fps=15",
+		"-loop", "0",
+		"-an",
+		outputPath,
+	)
+	var errBuf bytes.Buffer
+	cmd.Stderr = &errBuf
+	if err := cmd.Run(); err != nil {
+		os.RemoveAll(outDir)
+		return "", fmt.Errorf("ffmpeg animated preview generation failed for '%s': %w. Stderr: %s", filePath, err, errBuf.String())
+	}
+
+	return outputPath, nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/jobs"
+	"github.com/google/uuid"
+	"github.com/kkdai/youtube/v2"
+)
+
+// maxIngestSizeBytes mirrors the upload handler's 1 GB cap so a YouTube
+// ingest can't be used to fill the server's disk.
+const maxIngestSizeBytes = 1 << 30
+
+type ingestYouTubeRequest struct {
+	YouTubeURL string `json:"youtube_url"`
+}
+
+// handlerIngestYouTube handles POST /api/videos/{videoID}/ingest, fetching a
+// YouTube video's highest-quality progressive MP4 stream server-side and
+// feeding it into the same processing pipeline as handlerUploadVideo.
+func (cfg *apiConfig) handlerIngestYouTube(w http.ResponseWriter, r *http.Request) {
+	videoIDString := r.PathValue("videoID")
+	videoID, err := uuid.Parse(videoIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid video ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(videoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't find video", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Not authorized to update this video", nil)
+		return
+	}
+
+	var reqBody ingestYouTubeRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body", err)
+		return
+	}
+	if reqBody.YouTubeURL == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing youtube_url", nil)
+		return
+	}
+
+	client := youtube.Client{}
+	ytVideo, err := client.GetVideo(reqBody.YouTubeURL)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't fetch YouTube video", err)
+		return
+	}
+
+	format, err := highestQualityProgressiveMP4(ytVideo.Formats)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "No suitable progressive MP4 stream found", err)
+		return
+	}
+
+	stream, _, err := client.GetStream(ytVideo, format)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't open YouTube stream", err)
+		return
+	}
+	defer stream.Close()
+
+	tmpFile, err := os.CreateTemp("", "tubely-ingest-*.mp4")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create temporary file on server", err)
+		return
+	}
+
+	limited := io.LimitReader(stream, maxIngestSizeBytes)
+	lastLoggedPercent := -1
+	progress := jobs.NewProgressReader(limited, format.ContentLength, func(percent int) {
+		// Read is called once per copy-buffer (~32KB), which would be tens
+		// of thousands of log lines for a ~1GB video; only log on each new
+		// percentage point.
+		if percent == lastLoggedPercent {
+			return
+		}
+		lastLoggedPercent = percent
+		log.Printf("youtube ingest %s: %d%% downloaded", videoID, percent)
+	})
+
+	if _, err := io.Copy(tmpFile, progress); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Error downloading YouTube video", err)
+		return
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Error finalizing temp file", err)
+		return
+	}
+
+	job := jobs.Job{
+		ID:          uuid.New(),
+		VideoID:     videoID,
+		UserID:      userID,
+		FilePath:    tmpFile.Name(),
+		ContentType: "video/mp4",
+	}
+	if err := cfg.jobQueue.Enqueue(job); err != nil {
+		os.Remove(tmpFile.Name())
+		if errors.Is(err, jobs.ErrQueueFull) {
+			respondWithError(w, http.StatusServiceUnavailable, "Video processing queue is full, please retry shortly", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Error enqueueing video processing job", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, struct {
+		JobID uuid.UUID `json:"job_id"`
+	}{JobID: job.ID})
+}
+
+// highestQualityProgressiveMP4 picks the progressive (video+audio in one
+// file) MP4 format with the highest bitrate out of formats. Adaptive (video
+// only or audio only) and non-MP4 formats are rejected.
+func highestQualityProgressiveMP4(formats youtube.FormatList) (*youtube.Format, error) {
+	var best *youtube.Format
+	for i := range formats {
+		f := &formats[i]
+		if !strings.HasPrefix(f.MimeType, "video/mp4") {
+			continue
+		}
+		if f.AudioChannels == 0 {
+			// Adaptive video-only stream; we want progressive only.
+			continue
+		}
+		if best == nil || f.Bitrate > best.Bitrate {
+			best = f
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no progressive video/mp4 format with audio was offered for this video")
+	}
+	return best, nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/jobs"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/uploads"
+	"github.com/google/uuid"
+)
+
+// uploadPartSize is the chunk size clients should send for each part. S3
+// requires every part but the last to be at least 5 MiB.
+const uploadPartSize = 8 << 20 // 8 MiB
+
+type openUploadRequest struct {
+	VideoID     uuid.UUID `json:"video_id"`
+	ContentType string    `json:"content_type"`
+}
+
+type openUploadResponse struct {
+	UploadID uuid.UUID `json:"upload_id"`
+	PartSize int64     `json:"part_size"`
+}
+
+// handlerOpenUpload handles POST /api/uploads, opening a resumable,
+// S3-multipart-backed upload session for a video the caller owns.
+func (cfg *apiConfig) handlerOpenUpload(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	var reqBody openUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid JSON body", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(reqBody.VideoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't find video", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Not authorized to update this video", nil)
+		return
+	}
+
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to generate secure file key", err)
+		return
+	}
+	s3Key := "uploads/" + hex.EncodeToString(randomBytes) + ".mp4"
+
+	created, err := cfg.s3Client.CreateMultipartUpload(r.Context(), &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(cfg.s3Bucket),
+		Key:         aws.String(s3Key),
+		ContentType: aws.String(reqBody.ContentType),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't start multipart upload", err)
+		return
+	}
+
+	session := uploads.Session{
+		ID:          uuid.New(),
+		VideoID:     reqBody.VideoID,
+		UserID:      userID,
+		S3Key:       s3Key,
+		S3UploadID:  aws.ToString(created.UploadId),
+		ContentType: reqBody.ContentType,
+		PartSize:    uploadPartSize,
+	}
+	if err := cfg.uploadSessions.CreateSession(session); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't persist upload session", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, openUploadResponse{
+		UploadID: session.ID,
+		PartSize: session.PartSize,
+	})
+}
+
+// handlerUploadPart handles PUT /api/uploads/{uploadID}/parts/{n}, proxying
+// a single chunk straight through to S3 so it never fully buffers on disk.
+func (cfg *apiConfig) handlerUploadPart(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	uploadID, err := uuid.Parse(r.PathValue("uploadID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid upload ID", err)
+		return
+	}
+	partNumber, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil || partNumber < 1 {
+		respondWithError(w, http.StatusBadRequest, "Invalid part number", err)
+		return
+	}
+
+	session, err := cfg.uploadSessions.GetSession(uploadID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Upload session not found", err)
+		return
+	}
+	if session.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Not authorized to write to this upload", nil)
+		return
+	}
+
+	result, err := cfg.s3Client.UploadPart(r.Context(), &s3.UploadPartInput{
+		Bucket:     aws.String(cfg.s3Bucket),
+		Key:        aws.String(session.S3Key),
+		UploadId:   aws.String(session.S3UploadID),
+		PartNumber: aws.Int32(int32(partNumber)),
+		Body:       r.Body,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload part", err)
+		return
+	}
+
+	part := uploads.PartInfo{PartNumber: int32(partNumber), ETag: aws.ToString(result.ETag)}
+	if err := cfg.uploadSessions.AddPart(uploadID, part); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't persist part state", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct {
+		PartNumber int32  `json:"part_number"`
+		ETag       string `json:"etag"`
+	}{PartNumber: part.PartNumber, ETag: part.ETag})
+}
+
+// handlerCompleteUpload handles POST /api/uploads/{uploadID}/complete,
+// finalizing the S3 multipart upload and handing the assembled video off to
+// the existing background processing pipeline.
+func (cfg *apiConfig) handlerCompleteUpload(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	uploadID, err := uuid.Parse(r.PathValue("uploadID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid upload ID", err)
+		return
+	}
+
+	session, err := cfg.uploadSessions.GetSession(uploadID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Upload session not found", err)
+		return
+	}
+	if session.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Not authorized to complete this upload", nil)
+		return
+	}
+	if len(session.Parts) == 0 {
+		respondWithError(w, http.StatusBadRequest, "No parts have been uploaded yet", nil)
+		return
+	}
+
+	sortedParts := append([]uploads.PartInfo(nil), session.Parts...)
+	sort.Slice(sortedParts, func(i, j int) bool { return sortedParts[i].PartNumber < sortedParts[j].PartNumber })
+
+	completedParts := make([]types.CompletedPart, len(sortedParts))
+	for i, part := range sortedParts {
+		completedParts[i] = types.CompletedPart{
+			PartNumber: aws.Int32(part.PartNumber),
+			ETag:       aws.String(part.ETag),
+		}
+	}
+
+	_, err = cfg.s3Client.CompleteMultipartUpload(r.Context(), &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(cfg.s3Bucket),
+		Key:             aws.String(session.S3Key),
+		UploadId:        aws.String(session.S3UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completedParts},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't complete multipart upload", err)
+		return
+	}
+
+	// ffmpeg needs a local file to probe/transcode, so we download the
+	// assembled object once here and defer the rest of the pipeline to the
+	// existing job queue rather than re-implementing it against a stream.
+	tmpFile, err := os.CreateTemp("", "tubely-multipart-*.mp4")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create temporary file on server", err)
+		return
+	}
+
+	getResult, err := cfg.s3Client.GetObject(r.Context(), &s3.GetObjectInput{
+		Bucket: aws.String(cfg.s3Bucket),
+		Key:    aws.String(session.S3Key),
+	})
+	if err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Couldn't download assembled video", err)
+		return
+	}
+	_, err = io.Copy(tmpFile, getResult.Body)
+	getResult.Body.Close()
+	if err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Couldn't download assembled video", err)
+		return
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Error finalizing temp file", err)
+		return
+	}
+
+	job := jobs.Job{
+		ID:          uuid.New(),
+		VideoID:     session.VideoID,
+		UserID:      userID,
+		FilePath:    tmpFile.Name(),
+		ContentType: session.ContentType,
+	}
+	if err := cfg.jobQueue.Enqueue(job); err != nil {
+		os.Remove(tmpFile.Name())
+		if errors.Is(err, jobs.ErrQueueFull) {
+			respondWithError(w, http.StatusServiceUnavailable, "Video processing queue is full, please retry shortly", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Error enqueueing video processing job", err)
+		return
+	}
+
+	_ = cfg.uploadSessions.DeleteSession(uploadID)
+
+	respondWithJSON(w, http.StatusAccepted, struct {
+		JobID uuid.UUID `json:"job_id"`
+	}{JobID: job.ID})
+}
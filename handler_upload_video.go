@@ -1,18 +1,14 @@
 package main
 
 import (
-	"context"
-	"crypto/rand"
-	"encoding/hex"
-	"fmt" // For constructing the S3 URL string
+	"errors"
 	"io"
 	"mime"
 	"net/http"
 	"os"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth" // Assuming this path is correct
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/jobs"
 	"github.com/google/uuid"
 )
 
@@ -126,110 +122,38 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 		)
 		return
 	}
-	// Defer LIFO: tmpFile.Close() runs before os.Remove()
-	defer tmpFile.Close()
-	defer os.Remove(tmpFile.Name()) // Clean up temp file
-
+	// The temp file outlives this request: it is closed here, and the job
+	// queue's worker removes it once processing finishes (or fails).
 	if _, err = io.Copy(tmpFile, file); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
 		respondWithError(w, http.StatusInternalServerError, "Error saving uploaded file to temp file", err)
 		return
 	}
-
-	optimizedPath, err := cfg.processVideoForFastStart(tmpFile.Name())
-	if err != nil {
-		respondWithError(
-			w,
-			http.StatusInternalServerError,
-			"Error optimizing video for streaming",
-			err,
-		)
-		return
-	}
-
-	// Reopen the optimized file for reading since the original file has been replaced
-	tmpFile.Close()
-	tmpFile, err = os.Open(optimizedPath)
-	if err != nil {
-		respondWithError(
-			w,
-			http.StatusInternalServerError,
-			"Error opening optimized video file",
-			err,
-		)
-		return
-	}
-
-	// Reset the tempFile's file pointer to the beginning
-	if _, err = tmpFile.Seek(0, io.SeekStart); err != nil {
-		respondWithError(
-			w,
-			http.StatusInternalServerError,
-			"Unable to seek in temp file",
-			err,
-		)
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Error finalizing temp file", err)
 		return
 	}
 
-	// Assignment: File key <random-32-byte-hex>.ext
-	randomBytes := make([]byte, 16) // 16 bytes = 32 hex characters
-	if _, err := rand.Read(randomBytes); err != nil {
-		respondWithError(
-			w,
-			http.StatusInternalServerError,
-			"Failed to generate secure file key",
-			err,
-		)
-		return
-	}
-	// check orienation of the video file
-	ratio, err := cfg.getVideoAspectRatio(tmpFile.Name())
-	if err != nil {
-		respondWithError(
-			w,
-			http.StatusInternalServerError,
-			"Error getting video aspect ratio",
-			err,
-		)
-	}
-	var prefix = "other"
-	if ratio == "16:9" {
-		prefix = "landscape"
-	} else if ratio == "9:16" {
-		prefix = "portrait"
+	job := jobs.Job{
+		ID:          uuid.New(),
+		VideoID:     videoID,
+		UserID:      userID,
+		FilePath:    tmpFile.Name(),
+		ContentType: contentType,
 	}
-	s3FileKey := prefix + "/" + hex.EncodeToString(randomBytes) + ".mp4"
-
-	// Put the object into S3
-	_, err = cfg.s3Client.PutObject(context.TODO(), &s3.PutObjectInput{
-		Bucket:      aws.String(cfg.s3Bucket),
-		Key:         aws.String(s3FileKey),
-		Body:        tmpFile,
-		ContentType: aws.String(contentType), // This should be "video/mp4"
-	})
-	if err != nil {
-		respondWithError(
-			w,
-			http.StatusInternalServerError,
-			"Error uploading video to S3",
-			err,
-		)
-		return
-	}
-
-	// Assignment: Update VideoURL with S3 bucket and key.
-	// Format: https://<bucket-name>.s3.<region>.amazonaws.com/<key>
-	s3VideoURL := fmt.Sprintf(
-		"https://%s/%s",
-		cfg.s3CfDistribution,
-		s3FileKey,
-	)
-	video.VideoURL = aws.String(s3VideoURL) // Assuming video.VideoURL is *string
-
-	err = cfg.db.UpdateVideo(video)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't update video record in DB", err)
+	if err := cfg.jobQueue.Enqueue(job); err != nil {
+		os.Remove(tmpFile.Name())
+		if errors.Is(err, jobs.ErrQueueFull) {
+			respondWithError(w, http.StatusServiceUnavailable, "Video processing queue is full, please retry shortly", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Error enqueueing video processing job", err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, video)
+	respondWithJSON(w, http.StatusAccepted, struct {
+		JobID uuid.UUID `json:"job_id"`
+	}{JobID: job.ID})
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/google/uuid"
+)
+
+// handlerGetVideoStatus handles GET /api/videos/{videoID}/status, reporting
+// the current state of that video's most recent background processing job.
+func (cfg *apiConfig) handlerGetVideoStatus(w http.ResponseWriter, r *http.Request) {
+	videoIDString := r.PathValue("videoID")
+	videoID, err := uuid.Parse(videoIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid video ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(videoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't find video", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Not authorized to view this video's status", nil)
+		return
+	}
+
+	job, err := cfg.jobQueue.GetStatus(videoID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "No processing job found for this video", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct {
+		State    string `json:"state"`
+		Progress int    `json:"progress"`
+		Error    string `json:"error,omitempty"`
+	}{
+		State:    string(job.State),
+		Progress: job.Progress,
+		Error:    job.Error,
+	})
+}
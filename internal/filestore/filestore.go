@@ -0,0 +1,24 @@
+// Package filestore abstracts the object storage backend videos and their
+// derived assets (HLS segments, thumbnails, previews) are written to, so the
+// rest of the app doesn't need to know whether it's talking to S3, a
+// self-hosted MinIO, or the local disk.
+package filestore
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// FileStore puts, presigns and deletes objects under a caller-chosen
+// key. Implementations are expected to be safe for concurrent use.
+type FileStore interface {
+	// Put uploads the contents of r under key with the given contentType
+	// and returns a URL the object can be fetched from.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) (url string, err error)
+	// PresignGet returns a time-limited URL for retrieving key, for
+	// backends that don't serve objects from a public URL directly.
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// Delete removes the object stored under key.
+	Delete(ctx context.Context, key string) error
+}
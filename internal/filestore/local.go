@@ -0,0 +1,57 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalFileStore writes objects under a root directory on local disk and
+// serves them back via a base URL (typically this server's own /assets/
+// route). It's meant for local development without AWS credentials.
+type LocalFileStore struct {
+	assetsRoot string
+	baseURL    string // e.g. "http://localhost:8091/assets"
+}
+
+// NewLocalFileStore returns a FileStore that writes under assetsRoot and
+// builds URLs against baseURL.
+func NewLocalFileStore(assetsRoot string, baseURL string) *LocalFileStore {
+	return &LocalFileStore{assetsRoot: assetsRoot, baseURL: baseURL}
+}
+
+func (l *LocalFileStore) Put(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	diskPath := filepath.Join(l.assetsRoot, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(diskPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create asset directory for '%s': %w", key, err)
+	}
+
+	f, err := os.Create(diskPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create asset file '%s': %w", key, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return "", fmt.Errorf("failed to write asset '%s': %w", key, err)
+	}
+
+	return fmt.Sprintf("%s/%s", l.baseURL, key), nil
+}
+
+func (l *LocalFileStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	// Local assets are served from a public URL already; there's nothing
+	// to presign.
+	return fmt.Sprintf("%s/%s", l.baseURL, key), nil
+}
+
+func (l *LocalFileStore) Delete(ctx context.Context, key string) error {
+	diskPath := filepath.Join(l.assetsRoot, filepath.FromSlash(key))
+	if err := os.Remove(diskPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete asset '%s': %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// MinIOFileStore stores objects in a self-hosted S3-compatible MinIO server.
+type MinIOFileStore struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string // base URL objects are served from, e.g. "https://minio.example.com/<bucket>"
+}
+
+// NewMinIOFileStore returns a FileStore backed by a MinIO bucket, serving
+// objects from publicURL.
+func NewMinIOFileStore(client *minio.Client, bucket string, publicURL string) *MinIOFileStore {
+	return &MinIOFileStore{client: client, bucket: bucket, publicURL: publicURL}
+}
+
+func (m *MinIOFileStore) Put(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	_, err := m.client.PutObject(ctx, m.bucket, key, r, -1, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload '%s' to MinIO: %w", key, err)
+	}
+	return fmt.Sprintf("%s/%s", m.publicURL, key), nil
+}
+
+func (m *MinIOFileStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	u, err := m.client.PresignedGetObject(ctx, m.bucket, key, ttl, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign '%s': %w", key, err)
+	}
+	return u.String(), nil
+}
+
+func (m *MinIOFileStore) Delete(ctx context.Context, key string) error {
+	if err := m.client.RemoveObject(ctx, m.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete '%s' from MinIO: %w", key, err)
+	}
+	return nil
+}
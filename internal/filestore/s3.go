@@ -0,0 +1,66 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3FileStore stores objects in an AWS S3 bucket and serves them through a
+// CloudFront distribution.
+type S3FileStore struct {
+	client         *s3.Client
+	presignClient  *s3.PresignClient
+	bucket         string
+	cfDistribution string
+}
+
+// NewS3FileStore returns a FileStore backed by bucket, with URLs built
+// against cfDistribution (e.g. "d111abcdef.cloudfront.net").
+func NewS3FileStore(client *s3.Client, bucket string, cfDistribution string) *S3FileStore {
+	return &S3FileStore{
+		client:         client,
+		presignClient:  s3.NewPresignClient(client),
+		bucket:         bucket,
+		cfDistribution: cfDistribution,
+	}
+}
+
+func (s *S3FileStore) Put(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        r,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload '%s' to S3: %w", key, err)
+	}
+	return fmt.Sprintf("https://%s/%s", s.cfDistribution, key), nil
+}
+
+func (s *S3FileStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	req, err := s.presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign '%s': %w", key, err)
+	}
+	return req.URL, nil
+}
+
+func (s *S3FileStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete '%s' from S3: %w", key, err)
+	}
+	return nil
+}
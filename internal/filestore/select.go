@@ -0,0 +1,72 @@
+package filestore
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Backend identifies which FileStore implementation to construct.
+type Backend string
+
+const (
+	BackendS3    Backend = "s3"
+	BackendMinIO Backend = "minio"
+	BackendLocal Backend = "local"
+)
+
+// BackendFromEnv reads TUBELY_STORAGE_BACKEND, defaulting to "s3" so
+// existing deployments keep working without changes.
+func BackendFromEnv() Backend {
+	switch Backend(os.Getenv("TUBELY_STORAGE_BACKEND")) {
+	case BackendMinIO:
+		return BackendMinIO
+	case BackendLocal:
+		return BackendLocal
+	default:
+		return BackendS3
+	}
+}
+
+// NewFromEnv constructs the FileStore selected by TUBELY_STORAGE_BACKEND.
+// s3Client/s3Bucket/cfDistribution are reused for the "s3" backend; assetsRoot
+// and localBaseURL are used for the "local" backend. The "minio" backend is
+// configured entirely from TUBELY_MINIO_* env vars so it can run without any
+// AWS credentials present.
+func NewFromEnv(s3Client *s3.Client, s3Bucket string, cfDistribution string, assetsRoot string, localBaseURL string) (FileStore, error) {
+	switch BackendFromEnv() {
+	case BackendLocal:
+		return NewLocalFileStore(assetsRoot, localBaseURL), nil
+	case BackendMinIO:
+		return newMinIOFileStoreFromEnv()
+	default:
+		return NewS3FileStore(s3Client, s3Bucket, cfDistribution), nil
+	}
+}
+
+func newMinIOFileStoreFromEnv() (*MinIOFileStore, error) {
+	endpoint := os.Getenv("TUBELY_MINIO_ENDPOINT")
+	accessKey := os.Getenv("TUBELY_MINIO_ACCESS_KEY")
+	secretKey := os.Getenv("TUBELY_MINIO_SECRET_KEY")
+	bucket := os.Getenv("TUBELY_MINIO_BUCKET")
+	publicURL := os.Getenv("TUBELY_MINIO_PUBLIC_URL")
+	useSSL, _ := strconv.ParseBool(os.Getenv("TUBELY_MINIO_USE_SSL"))
+
+	if endpoint == "" || bucket == "" {
+		return nil, fmt.Errorf("TUBELY_MINIO_ENDPOINT and TUBELY_MINIO_BUCKET are required for the minio storage backend")
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MinIO client: %w", err)
+	}
+
+	return NewMinIOFileStore(client, bucket, publicURL), nil
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import "io"
+
+// progressReader wraps an io.Reader, invoking onProgress with the percent
+// complete (0-100) each time bytes are read. total is the expected number
+// of bytes to be read; if it's <= 0, onProgress is never called since
+// percent can't be computed.
+type progressReader struct {
+	r          io.Reader
+	total      int64
+	read       int64
+	onProgress func(percent int)
+}
+
+// NewProgressReader returns an io.Reader that reports read progress against
+// total bytes via onProgress as the underlying reader r is consumed.
+func NewProgressReader(r io.Reader, total int64, onProgress func(percent int)) io.Reader {
+	return &progressReader{r: r, total: total, onProgress: onProgress}
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	if n > 0 {
+		p.read += int64(n)
+		if p.total > 0 && p.onProgress != nil {
+			percent := int(p.read * 100 / p.total)
+			if percent > 100 {
+				percent = 100
+			}
+			p.onProgress(percent)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,144 @@
+// Package jobs implements a small in-process background job queue used to
+// move slow video processing work (ffmpeg, ffprobe, S3 uploads) out of the
+// request/response cycle.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// State is the lifecycle state of a video processing Job.
+type State string
+
+const (
+	StatePending     State = "pending"
+	StateProbing     State = "probing"
+	StateTranscoding State = "transcoding"
+	StateUploading   State = "uploading"
+	StateComplete    State = "complete"
+	StateFailed      State = "failed"
+)
+
+// Job tracks a single video's progress through the processing pipeline.
+type Job struct {
+	ID          uuid.UUID
+	VideoID     uuid.UUID
+	UserID      uuid.UUID
+	FilePath    string
+	ContentType string
+	State       State
+	Progress    int // 0-100
+	Error       string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Store persists Jobs so their state survives a worker restart and can be
+// queried by the status API. Implementations are expected to be safe for
+// concurrent use.
+type Store interface {
+	CreateJob(job Job) error
+	UpdateJob(job Job) error
+	GetJob(id uuid.UUID) (Job, error)
+	GetLatestJobForVideo(videoID uuid.UUID) (Job, error)
+}
+
+// ProcessFunc does the actual work for a job (probe, transcode, upload) and
+// reports progress via report. It should return a non-nil error for any
+// failure; VideoJobQueue takes care of persisting State/Error around it.
+type ProcessFunc func(ctx context.Context, job Job, report func(state State, percent int)) error
+
+// VideoJobQueue is a fixed-size worker pool consuming jobs from an
+// in-process channel. The channel backend can later be swapped for a
+// pluggable one (e.g. SQS, Redis) without changing callers, since producers
+// only ever see Enqueue/GetStatus.
+type VideoJobQueue struct {
+	store   Store
+	process ProcessFunc
+	queue   chan uuid.UUID
+}
+
+// NewVideoJobQueue creates a queue backed by store and starts workerCount
+// workers pulling from an in-process channel, each running process for the
+// jobs it dequeues.
+func NewVideoJobQueue(ctx context.Context, store Store, workerCount int, process ProcessFunc) *VideoJobQueue {
+	q := &VideoJobQueue{
+		store:   store,
+		process: process,
+		queue:   make(chan uuid.UUID, 64),
+	}
+	for i := 0; i < workerCount; i++ {
+		go q.worker(ctx)
+	}
+	return q
+}
+
+// ErrQueueFull is returned by Enqueue when every worker is busy and the
+// backlog channel is already at capacity.
+var ErrQueueFull = fmt.Errorf("job queue is full, try again shortly")
+
+// Enqueue persists job in the pending state and schedules it for pickup by
+// a worker. The handoff to the worker pool is non-blocking: callers run on
+// an HTTP request goroutine and must be able to return 202 Accepted
+// immediately rather than stall behind a full backlog.
+func (q *VideoJobQueue) Enqueue(job Job) error {
+	job.State = StatePending
+	job.Progress = 0
+	if err := q.store.CreateJob(job); err != nil {
+		return fmt.Errorf("failed to persist job: %w", err)
+	}
+	select {
+	case q.queue <- job.ID:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// GetStatus returns the most recent job for videoID.
+func (q *VideoJobQueue) GetStatus(videoID uuid.UUID) (Job, error) {
+	return q.store.GetLatestJobForVideo(videoID)
+}
+
+func (q *VideoJobQueue) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case jobID, ok := <-q.queue:
+			if !ok {
+				return
+			}
+			q.runJob(ctx, jobID)
+		}
+	}
+}
+
+func (q *VideoJobQueue) runJob(ctx context.Context, jobID uuid.UUID) {
+	job, err := q.store.GetJob(jobID)
+	if err != nil {
+		return
+	}
+
+	report := func(state State, percent int) {
+		job.State = state
+		job.Progress = percent
+		_ = q.store.UpdateJob(job)
+	}
+
+	if err := q.process(ctx, job, report); err != nil {
+		job.State = StateFailed
+		job.Error = err.Error()
+		_ = q.store.UpdateJob(job)
+		return
+	}
+
+	job.State = StateComplete
+	job.Progress = 100
+	job.Error = ""
+	_ = q.store.UpdateJob(job)
+}
@@ -0,0 +1,185 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore is a Store backed by an in-memory map. It's fast and simple
+// but doesn't survive a process restart and isn't visible across replicas;
+// prefer JSONStore for anything other than local development or tests.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	jobsByID    map[uuid.UUID]Job
+	latestByVid map[uuid.UUID]uuid.UUID
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobsByID:    make(map[uuid.UUID]Job),
+		latestByVid: make(map[uuid.UUID]uuid.UUID),
+	}
+}
+
+func (s *MemoryStore) CreateJob(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobsByID[job.ID] = job
+	s.latestByVid[job.VideoID] = job.ID
+	return nil
+}
+
+func (s *MemoryStore) UpdateJob(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobsByID[job.ID]; !ok {
+		return fmt.Errorf("job %s not found", job.ID)
+	}
+	s.jobsByID[job.ID] = job
+	return nil
+}
+
+func (s *MemoryStore) GetJob(id uuid.UUID) (Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobsByID[id]
+	if !ok {
+		return Job{}, fmt.Errorf("job %s not found", id)
+	}
+	return job, nil
+}
+
+func (s *MemoryStore) GetLatestJobForVideo(videoID uuid.UUID) (Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.latestByVid[videoID]
+	if !ok {
+		return Job{}, fmt.Errorf("no job found for video %s", videoID)
+	}
+	return s.jobsByID[id], nil
+}
+
+// jsonStoreData is the on-disk shape of a JSONStore's file.
+type jsonStoreData struct {
+	Jobs        map[uuid.UUID]Job       `json:"jobs"`
+	LatestByVid map[uuid.UUID]uuid.UUID `json:"latest_by_video"`
+}
+
+// JSONStore is a Store backed by a JSON file on disk, so job state (and
+// therefore the /status endpoint) survives a worker restart or redeploy and
+// is visible to every replica pointed at the same file (e.g. a shared
+// volume). Every call re-reads the file before mutating and writes it back
+// via a tmp-file-plus-rename so a crash mid-write can't leave a half-written
+// file behind.
+type JSONStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONStore returns a JSONStore backed by the file at path, creating it
+// (empty) if it doesn't already exist.
+func NewJSONStore(path string) (*JSONStore, error) {
+	s := &JSONStore{path: path}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		empty := jsonStoreData{
+			Jobs:        make(map[uuid.UUID]Job),
+			LatestByVid: make(map[uuid.UUID]uuid.UUID),
+		}
+		if err := s.write(empty); err != nil {
+			return nil, fmt.Errorf("failed to initialize job store file: %w", err)
+		}
+	}
+	return s, nil
+}
+
+func (s *JSONStore) read() (jsonStoreData, error) {
+	var data jsonStoreData
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return data, fmt.Errorf("failed to read job store file: %w", err)
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return data, fmt.Errorf("failed to parse job store file: %w", err)
+	}
+	if data.Jobs == nil {
+		data.Jobs = make(map[uuid.UUID]Job)
+	}
+	if data.LatestByVid == nil {
+		data.LatestByVid = make(map[uuid.UUID]uuid.UUID)
+	}
+	return data, nil
+}
+
+func (s *JSONStore) write(data jsonStoreData) error {
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal job store data: %w", err)
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, raw, 0o644); err != nil {
+		return fmt.Errorf("failed to write job store file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to finalize job store file: %w", err)
+	}
+	return nil
+}
+
+func (s *JSONStore) CreateJob(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	data.Jobs[job.ID] = job
+	data.LatestByVid[job.VideoID] = job.ID
+	return s.write(data)
+}
+
+func (s *JSONStore) UpdateJob(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := data.Jobs[job.ID]; !ok {
+		return fmt.Errorf("job %s not found", job.ID)
+	}
+	data.Jobs[job.ID] = job
+	return s.write(data)
+}
+
+func (s *JSONStore) GetJob(id uuid.UUID) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return Job{}, err
+	}
+	job, ok := data.Jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("job %s not found", id)
+	}
+	return job, nil
+}
+
+func (s *JSONStore) GetLatestJobForVideo(videoID uuid.UUID) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return Job{}, err
+	}
+	id, ok := data.LatestByVid[videoID]
+	if !ok {
+		return Job{}, fmt.Errorf("no job found for video %s", videoID)
+	}
+	return data.Jobs[id], nil
+}
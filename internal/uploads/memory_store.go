@@ -0,0 +1,195 @@
+package uploads
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore is a Store backed by an in-memory map. It's fast and simple
+// but doesn't survive a process restart; prefer JSONStore for anything other
+// than local development or tests.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]Session
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[uuid.UUID]Session)}
+}
+
+func (s *MemoryStore) CreateSession(session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = session
+	return nil
+}
+
+func (s *MemoryStore) GetSession(id uuid.UUID) (Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return Session{}, fmt.Errorf("upload session %s not found", id)
+	}
+	return session, nil
+}
+
+// AddPart upserts part by PartNumber: a client retrying a dropped PUT for a
+// part it already sent replaces the stale ETag instead of appending a
+// duplicate, which would otherwise make CompleteMultipartUpload reject the
+// session's part list.
+func (s *MemoryStore) AddPart(id uuid.UUID, part PartInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return fmt.Errorf("upload session %s not found", id)
+	}
+	replaced := false
+	for i, existing := range session.Parts {
+		if existing.PartNumber == part.PartNumber {
+			session.Parts[i] = part
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		session.Parts = append(session.Parts, part)
+	}
+	s.sessions[id] = session
+	return nil
+}
+
+func (s *MemoryStore) DeleteSession(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// jsonStoreData is the on-disk shape of a JSONStore's file.
+type jsonStoreData struct {
+	Sessions map[uuid.UUID]Session `json:"sessions"`
+}
+
+// JSONStore is a Store backed by a JSON file on disk, so a session's parts
+// and ETags survive a process restart or redeploy mid-upload instead of
+// orphaning the S3 multipart upload and forcing the client to start over.
+// Every call re-reads the file before mutating and writes it back via a
+// tmp-file-plus-rename so a crash mid-write can't leave a half-written file
+// behind.
+type JSONStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJSONStore returns a JSONStore backed by the file at path, creating it
+// (empty) if it doesn't already exist.
+func NewJSONStore(path string) (*JSONStore, error) {
+	s := &JSONStore{path: path}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		empty := jsonStoreData{Sessions: make(map[uuid.UUID]Session)}
+		if err := s.write(empty); err != nil {
+			return nil, fmt.Errorf("failed to initialize upload session store file: %w", err)
+		}
+	}
+	return s, nil
+}
+
+func (s *JSONStore) read() (jsonStoreData, error) {
+	var data jsonStoreData
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return data, fmt.Errorf("failed to read upload session store file: %w", err)
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return data, fmt.Errorf("failed to parse upload session store file: %w", err)
+	}
+	if data.Sessions == nil {
+		data.Sessions = make(map[uuid.UUID]Session)
+	}
+	return data, nil
+}
+
+func (s *JSONStore) write(data jsonStoreData) error {
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload session store data: %w", err)
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, raw, 0o644); err != nil {
+		return fmt.Errorf("failed to write upload session store file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to finalize upload session store file: %w", err)
+	}
+	return nil
+}
+
+func (s *JSONStore) CreateSession(session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	data.Sessions[session.ID] = session
+	return s.write(data)
+}
+
+func (s *JSONStore) GetSession(id uuid.UUID) (Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return Session{}, err
+	}
+	session, ok := data.Sessions[id]
+	if !ok {
+		return Session{}, fmt.Errorf("upload session %s not found", id)
+	}
+	return session, nil
+}
+
+// AddPart upserts part by PartNumber, same as MemoryStore.AddPart.
+func (s *JSONStore) AddPart(id uuid.UUID, part PartInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	session, ok := data.Sessions[id]
+	if !ok {
+		return fmt.Errorf("upload session %s not found", id)
+	}
+	replaced := false
+	for i, existing := range session.Parts {
+		if existing.PartNumber == part.PartNumber {
+			session.Parts[i] = part
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		session.Parts = append(session.Parts, part)
+	}
+	data.Sessions[id] = session
+	return s.write(data)
+}
+
+func (s *JSONStore) DeleteSession(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	delete(data.Sessions, id)
+	return s.write(data)
+}
@@ -0,0 +1,39 @@
+// Package uploads tracks resumable, S3-multipart-backed upload sessions so
+// large videos can be sent to the server in retryable chunks instead of one
+// giant request body.
+package uploads
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PartInfo records the ETag S3 returned for one uploaded part, needed to
+// complete the multipart upload later.
+type PartInfo struct {
+	PartNumber int32
+	ETag       string
+}
+
+// Session tracks a single in-progress resumable upload.
+type Session struct {
+	ID          uuid.UUID
+	VideoID     uuid.UUID
+	UserID      uuid.UUID
+	S3Key       string
+	S3UploadID  string
+	ContentType string
+	PartSize    int64
+	Parts       []PartInfo
+	CreatedAt   time.Time
+}
+
+// Store persists upload Sessions across requests, since a single resumable
+// upload is spread across many HTTP calls.
+type Store interface {
+	CreateSession(session Session) error
+	GetSession(id uuid.UUID) (Session, error)
+	AddPart(id uuid.UUID, part PartInfo) error
+	DeleteSession(id uuid.UUID) error
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/jobs"
+)
+
+// TranscodeProfile describes a single rung of the adaptive bitrate ladder.
+type TranscodeProfile struct {
+	Name         string // used as the HLS stream name, e.g. "720p"
+	Width        int
+	Height       int
+	VideoBitrate string // ffmpeg -b:v value, e.g. "2500k"
+	AudioBitrate string // ffmpeg -b:a value, e.g. "128k"
+	Codec        string // "h264" or "h265"
+}
+
+// defaultTranscodeLadder is the standard set of renditions we attempt to
+// produce for every uploaded video. Renditions taller than the source are
+// skipped by transcodeToHLS, so a 720p upload never gets a fabricated 1080p
+// rung.
+var defaultTranscodeLadder = []TranscodeProfile{
+	{Name: "240p", Width: 426, Height: 240, VideoBitrate: "400k", AudioBitrate: "64k", Codec: "h264"},
+	{Name: "480p", Width: 854, Height: 480, VideoBitrate: "1000k", AudioBitrate: "96k", Codec: "h264"},
+	{Name: "720p", Width: 1280, Height: 720, VideoBitrate: "2500k", AudioBitrate: "128k", Codec: "h264"},
+	{Name: "1080p", Width: 1920, Height: 1080, VideoBitrate: "5000k", AudioBitrate: "128k", Codec: "h264"},
+}
+
+// codecFFmpegName maps our Codec field to the ffmpeg encoder it should use.
+func codecFFmpegName(codec string) string {
+	if codec == "h265" {
+		return "libx265"
+	}
+	return "libx264"
+}
+
+// ffmpegAvailable reports whether an ffmpeg binary can be found on PATH.
+func ffmpegAvailable() bool {
+	_, err := exec.LookPath("ffmpeg")
+	return err == nil
+}
+
+// transcodeToHLS produces a multi-bitrate HLS ladder from the video at
+// filePath, writing the master playlist, per-rendition playlists and
+// segments into a freshly created temp directory. It returns the directory
+// and the path to the master playlist inside it. Renditions taller (by short
+// edge) than the source video are skipped so low-resolution uploads don't
+// get upscaled. defaultTranscodeLadder is defined in landscape terms, so for
+// a portrait source each rendition's width/height are swapped to keep the
+// source's orientation instead of squishing it into a landscape box.
+func (cfg apiConfig) transcodeToHLS(filePath string) (outputDir string, masterPlaylistPath string, err error) {
+	sourceWidth, sourceHeight, err := cfg.getVideoDimensions(filePath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to inspect source video: %w", err)
+	}
+
+	// defaultTranscodeLadder's Width/Height are landscape (long edge x short
+	// edge). For a portrait source the "height" ffprobe reports is the long
+	// edge, so gating and dimensions must compare against the short edge and
+	// swap W/H, or portrait uploads get the full landscape ladder squished
+	// into landscape boxes instead of a portrait-sized one.
+	isPortrait := sourceWidth < sourceHeight
+	shortEdge := sourceHeight
+	if isPortrait {
+		shortEdge = sourceWidth
+	}
+
+	var ladder []TranscodeProfile
+	for _, profile := range defaultTranscodeLadder {
+		if profile.Height <= shortEdge {
+			ladder = append(ladder, profile)
+		}
+	}
+	if len(ladder) == 0 {
+		// Source is smaller than our lowest rung; transcode it as-is, at its
+		// own size (rounded down to even, as the encoders require).
+		lowest := defaultTranscodeLadder[0]
+		lowest.Width, lowest.Height = sourceWidth&^1, sourceHeight&^1
+		if isPortrait {
+			// Stored in landscape terms; swapped back below.
+			lowest.Width, lowest.Height = lowest.Height, lowest.Width
+		}
+		lowest.Name = fmt.Sprintf("%dp", shortEdge&^1)
+		ladder = []TranscodeProfile{lowest}
+	}
+
+	outputDir, err = os.MkdirTemp("", "tubely-hls-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create HLS output dir: %w", err)
+	}
+
+	args := []string{"-i", filePath}
+	var varStreamMap []string
+	for i, profile := range ladder {
+		width, height := profile.Width, profile.Height
+		if isPortrait {
+			width, height = height, width
+		}
+		args = append(args,
+			"-map", "0:v:0", "-map", "0:a:0",
+			fmt.Sprintf("-c:v:%d", i), codecFFmpegName(profile.Codec),
+			fmt.Sprintf("-b:v:%d", i), profile.VideoBitrate,
+			fmt.Sprintf("-s:v:%d", i), fmt.Sprintf("%dx%d", width, height),
+			fmt.Sprintf("-c:a:%d", i), "aac",
+			fmt.Sprintf("-b:a:%d", i), profile.AudioBitrate,
+		)
+		varStreamMap = append(varStreamMap, fmt.Sprintf("v:%d,a:%d,name:%s", i, i, profile.Name))
+	}
+
+	args = append(args,
+		"-f", "hls",
+		"-hls_time", "6",
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(outputDir, "%v_%03d.ts"),
+		"-master_pl_name", "master.m3u8",
+		"-var_stream_map", strings.Join(varStreamMap, " "),
+		filepath.Join(outputDir, "%v.m3u8"),
+	)
+
+	cmd := exec.Command("ffmpeg", args...)
+	var errBuf bytes.Buffer
+	cmd.Stderr = &errBuf
+	if err := cmd.Run(); err != nil {
+		os.RemoveAll(outputDir)
+		return "", "", fmt.Errorf("ffmpeg HLS transcode failed: %w. Stderr: %s", err, errBuf.String())
+	}
+
+	masterPlaylistPath = filepath.Join(outputDir, "master.m3u8")
+	if _, err := os.Stat(masterPlaylistPath); err != nil {
+		os.RemoveAll(outputDir)
+		return "", "", fmt.Errorf("master playlist was not produced: %w", err)
+	}
+
+	return outputDir, masterPlaylistPath, nil
+}
+
+// contentTypeForHLSFile returns the Content-Type to upload an HLS ladder
+// output file under, based on its extension.
+func contentTypeForHLSFile(name string) string {
+	switch filepath.Ext(name) {
+	case ".m3u8":
+		return "application/vnd.apple.mpegurl"
+	case ".ts":
+		return "video/MP2T"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// transcodeAndUploadLadder runs transcodeToHLS against the already
+// faststart-optimized video at faststartPath, uploads every file the ladder
+// produced through cfg.fileStore under a per-video prefix, and returns the
+// URL of the master playlist. report, if non-nil, is called with overall
+// upload progress (0-100) weighted by each file's size across the whole
+// ladder, so a status API poller sees real byte-level progress rather than
+// a single upload-phase stage.
+func (cfg apiConfig) transcodeAndUploadLadder(faststartPath string, fileKey string, report func(percent int)) (string, error) {
+	hlsDir, masterPlaylistPath, err := cfg.transcodeToHLS(faststartPath)
+	if err != nil {
+		return "", fmt.Errorf("error transcoding HLS ladder: %w", err)
+	}
+	defer os.RemoveAll(hlsDir)
+
+	keyPrefix := "hls/" + fileKey
+	entries, err := os.ReadDir(hlsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read HLS output dir: %w", err)
+	}
+
+	var totalBytes int64
+	sizes := make(map[string]int64, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return "", fmt.Errorf("failed to stat HLS output file '%s': %w", entry.Name(), err)
+		}
+		sizes[entry.Name()] = info.Size()
+		totalBytes += info.Size()
+	}
+
+	var uploadedBytes int64
+	var masterURL string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		diskPath := filepath.Join(hlsDir, entry.Name())
+		baseline := uploadedBytes
+		fileSize := sizes[entry.Name()]
+		url, err := cfg.uploadHLSFile(diskPath, keyPrefix+"/"+entry.Name(), func(filePercent int) {
+			if report == nil || totalBytes == 0 {
+				return
+			}
+			report(int((baseline + int64(filePercent)*fileSize/100) * 100 / totalBytes))
+		})
+		if err != nil {
+			return "", err
+		}
+		uploadedBytes += fileSize
+		if diskPath == masterPlaylistPath {
+			masterURL = url
+		}
+	}
+	if masterURL == "" {
+		return "", fmt.Errorf("master playlist was never uploaded")
+	}
+	if report != nil {
+		report(100)
+	}
+
+	return masterURL, nil
+}
+
+// uploadHLSFile uploads a single HLS ladder output file through
+// cfg.fileStore under key, tagging it with the appropriate Content-Type for
+// a playlist or segment, and returns its URL. onProgress, if non-nil, is
+// called with this file's own upload progress (0-100) as it's read.
+func (cfg apiConfig) uploadHLSFile(diskPath string, key string, onProgress func(percent int)) (string, error) {
+	f, err := os.Open(diskPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open HLS output file '%s': %w", diskPath, err)
+	}
+	defer f.Close()
+
+	var body io.Reader = f
+	if onProgress != nil {
+		if info, err := f.Stat(); err == nil && info.Size() > 0 {
+			body = jobs.NewProgressReader(f, info.Size(), onProgress)
+		}
+	}
+
+	url, err := cfg.fileStore.Put(context.TODO(), key, body, contentTypeForHLSFile(diskPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to upload '%s': %w", key, err)
+	}
+	return url, nil
+}
+
+// uploadPlainMP4 uploads the given file as a single MP4 under an orientation
+// prefix, for use when ffmpeg isn't available to build an HLS ladder. It
+// returns the URL of the uploaded object. report, if non-nil, is called
+// with real upload progress (0-100) as the file is read.
+func (cfg apiConfig) uploadPlainMP4(file *os.File, fileKey string, contentType string, report func(percent int)) (string, error) {
+	if _, err := file.Seek(0, 0); err != nil {
+		return "", fmt.Errorf("unable to seek in temp file: %w", err)
+	}
+
+	ratio, err := cfg.getVideoAspectRatio(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("error getting video aspect ratio: %w", err)
+	}
+	key := orientationPrefix(ratio) + "/" + fileKey + ".mp4"
+
+	var body io.Reader = file
+	if report != nil {
+		if info, err := file.Stat(); err == nil && info.Size() > 0 {
+			body = jobs.NewProgressReader(file, info.Size(), report)
+		}
+	}
+
+	url, err := cfg.fileStore.Put(context.TODO(), key, body, contentType)
+	if err != nil {
+		return "", fmt.Errorf("error uploading video: %w", err)
+	}
+	return url, nil
+}
+
+// generateAndUploadPreviewAssets generates a thumbnail and an animated
+// preview loop from faststartPath and uploads both through cfg.fileStore
+// under the orientation prefix matching ratio. It returns their URLs.
+func (cfg apiConfig) generateAndUploadPreviewAssets(faststartPath string, fileKey string, ratio string) (thumbnailURL string, previewURL string, err error) {
+	prefix := orientationPrefix(ratio)
+
+	duration, err := cfg.getVideoDuration(faststartPath)
+	if err != nil {
+		return "", "", fmt.Errorf("error getting video duration: %w", err)
+	}
+
+	thumbnailPath, err := cfg.generateThumbnail(faststartPath, duration*0.1)
+	if err != nil {
+		return "", "", fmt.Errorf("error generating thumbnail: %w", err)
+	}
+	defer os.RemoveAll(filepath.Dir(thumbnailPath))
+
+	thumbnailURL, err = cfg.uploadAssetFile(thumbnailPath, "thumbnails/"+prefix+"/"+fileKey+".jpg", "image/jpeg")
+	if err != nil {
+		return "", "", err
+	}
+
+	previewPath, err := cfg.generateAnimatedPreview(faststartPath)
+	if err != nil {
+		return "", "", fmt.Errorf("error generating animated preview: %w", err)
+	}
+	defer os.RemoveAll(filepath.Dir(previewPath))
+
+	previewURL, err = cfg.uploadAssetFile(previewPath, "previews/"+prefix+"/"+fileKey+".webp", "image/webp")
+	if err != nil {
+		return "", "", err
+	}
+
+	return thumbnailURL, previewURL, nil
+}
+
+// uploadAssetFile uploads the file at diskPath through cfg.fileStore under
+// key with the given contentType and returns its URL.
+func (cfg apiConfig) uploadAssetFile(diskPath string, key string, contentType string) (string, error) {
+	f, err := os.Open(diskPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open '%s': %w", diskPath, err)
+	}
+	defer f.Close()
+
+	url, err := cfg.fileStore.Put(context.TODO(), key, f, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload '%s': %w", key, err)
+	}
+	return url, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/jobs"
+)
+
+// mapPercent rescales percent (0-100, progress within one pipeline stage)
+// into the [start, end] range that stage occupies in the job's overall
+// progress.
+func mapPercent(start, end, percent int) int {
+	return start + (end-start)*percent/100
+}
+
+// processVideoUploadJob is the jobs.ProcessFunc that does the actual
+// ffmpeg/ffprobe/file-store work for an uploaded video. It runs on a job queue
+// worker, outside of the originating HTTP request.
+func (cfg *apiConfig) processVideoUploadJob(ctx context.Context, job jobs.Job, report func(state jobs.State, percent int)) error {
+	defer os.Remove(job.FilePath)
+
+	video, err := cfg.db.GetVideo(job.VideoID)
+	if err != nil {
+		return fmt.Errorf("couldn't find video: %w", err)
+	}
+
+	report(jobs.StateProbing, 5)
+
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return fmt.Errorf("failed to generate secure file key: %w", err)
+	}
+	fileKey := hex.EncodeToString(randomBytes)
+
+	var videoURL, thumbnailURL, previewURL string
+	if ffmpegAvailable() {
+		report(jobs.StateTranscoding, 10)
+		faststartPath, err := cfg.processVideoForFastStart(job.FilePath)
+		if err != nil {
+			return fmt.Errorf("error optimizing video for streaming: %w", err)
+		}
+
+		ratio, err := cfg.getVideoAspectRatio(faststartPath)
+		if err != nil {
+			return fmt.Errorf("error getting video aspect ratio: %w", err)
+		}
+
+		report(jobs.StateTranscoding, 25)
+		videoURL, err = cfg.transcodeAndUploadLadder(faststartPath, fileKey, func(percent int) {
+			report(jobs.StateUploading, mapPercent(25, 80, percent))
+		})
+		if err != nil {
+			return fmt.Errorf("error transcoding video for adaptive streaming: %w", err)
+		}
+
+		report(jobs.StateUploading, 80)
+		thumbnailURL, previewURL, err = cfg.generateAndUploadPreviewAssets(faststartPath, fileKey, ratio)
+		if err != nil {
+			return fmt.Errorf("error generating preview assets: %w", err)
+		}
+	} else {
+		report(jobs.StateUploading, 50)
+		f, err := os.Open(job.FilePath)
+		if err != nil {
+			return fmt.Errorf("error reopening temp file: %w", err)
+		}
+		defer f.Close()
+		videoURL, err = cfg.uploadPlainMP4(f, fileKey, job.ContentType, func(percent int) {
+			report(jobs.StateUploading, mapPercent(50, 95, percent))
+		})
+		if err != nil {
+			return fmt.Errorf("error uploading video: %w", err)
+		}
+	}
+
+	report(jobs.StateUploading, 95)
+
+	video.VideoURL = aws.String(videoURL)
+	if thumbnailURL != "" {
+		video.ThumbnailURL = aws.String(thumbnailURL)
+	}
+	if previewURL != "" {
+		video.PreviewURL = aws.String(previewURL)
+	}
+	if err := cfg.db.UpdateVideo(video); err != nil {
+		return fmt.Errorf("couldn't update video record in DB: %w", err)
+	}
+
+	return nil
+}